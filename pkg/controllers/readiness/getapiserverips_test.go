@@ -0,0 +1,105 @@
+package readiness
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+	discoveryv1lister "k8s.io/client-go/listers/discovery/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func protocolPtr(p corev1.Protocol) *corev1.Protocol { return &p }
+func int32Ptr(i int32) *int32                        { return &i }
+func boolPtr(b bool) *bool                           { return &b }
+
+// TestGetAPIServerIPsDualStack verifies that a dual-stack apiserver's IPv4 and
+// IPv6 EndpointSlices both produce a probe target, and that they collapse to
+// the same identity since they're the same underlying node.
+func TestGetAPIServerIPsDualStack(t *testing.T) {
+	kasService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Namespace: corev1.NamespaceDefault, Name: "kubernetes"},
+		Spec: corev1.ServiceSpec{
+			Ports: []corev1.ServicePort{{
+				Port:       443,
+				Protocol:   corev1.ProtocolTCP,
+				TargetPort: intstr.FromInt(6443),
+			}},
+		},
+	}
+
+	nodeName := "master-1"
+	slices := []*discoveryv1.EndpointSlice{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      "kubernetes-ipv4",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "kubernetes"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+			Ports:       []discoveryv1.EndpointPort{{Protocol: protocolPtr(corev1.ProtocolTCP), Port: int32Ptr(6443)}},
+			Endpoints: []discoveryv1.Endpoint{{
+				Addresses:  []string{"10.0.0.1"},
+				NodeName:   &nodeName,
+				Conditions: discoveryv1.EndpointConditions{Ready: boolPtr(true)},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      "kubernetes-ipv6",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "kubernetes"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv6,
+			Ports:       []discoveryv1.EndpointPort{{Protocol: protocolPtr(corev1.ProtocolTCP), Port: int32Ptr(6443)}},
+			Endpoints: []discoveryv1.Endpoint{{
+				Addresses:  []string{"fd00::1"},
+				NodeName:   &nodeName,
+				Conditions: discoveryv1.EndpointConditions{Ready: boolPtr(true)},
+			}},
+		},
+	}
+
+	serviceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := serviceIndexer.Add(kasService); err != nil {
+		t.Fatal(err)
+	}
+
+	sliceIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	for _, slice := range slices {
+		if err := sliceIndexer.Add(slice); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c := &wellKnownReadyController{
+		serviceLister:       corev1lister.NewServiceLister(serviceIndexer),
+		endpointSliceLister: discoveryv1lister.NewEndpointSliceLister(sliceIndexer),
+	}
+
+	targets, err := c.getAPIServerIPs()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("expected 2 probe targets (one per family), got %d: %#v", len(targets), targets)
+	}
+
+	identities := sets.NewString()
+	families := sets.NewString()
+	for _, target := range targets {
+		identities.Insert(target.identity)
+		families.Insert(string(target.family))
+	}
+
+	if identities.Len() != 1 {
+		t.Fatalf("expected both addresses to collapse to a single identity, got %v", identities.List())
+	}
+	if !families.HasAll(string(corev1.IPv4Protocol), string(corev1.IPv6Protocol)) {
+		t.Fatalf("expected both IPv4 and IPv6 families to be probed, got %v", families.List())
+	}
+}
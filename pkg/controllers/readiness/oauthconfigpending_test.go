@@ -0,0 +1,120 @@
+package readiness
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1lister "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	configv1 "github.com/openshift/api/config/v1"
+	configv1lister "github.com/openshift/client-go/config/listers/config/v1"
+)
+
+// TestWithinOAuthConfigGrace exercises the generation-keyed grace window
+// transitions directly: a new generation always starts a fresh window, and
+// the same generation is only excused until the window elapses.
+func TestWithinOAuthConfigGrace(t *testing.T) {
+	c := &wellKnownReadyController{}
+
+	if !c.withinOAuthConfigGrace(1) {
+		t.Fatal("expected the first observation of a generation to start a fresh grace window")
+	}
+
+	c.oauthGrace.since = time.Now().Add(-(oauthConfigGraceWindow + time.Second))
+	if c.withinOAuthConfigGrace(1) {
+		t.Fatal("expected the grace window to have expired for the same generation")
+	}
+
+	if !c.withinOAuthConfigGrace(2) {
+		t.Fatal("expected a new generation to reset the grace window")
+	}
+}
+
+// TestOAuthConfigPendingGatesOnConfigMapGeneration verifies that a metadata
+// mismatch stays "pending" only while the oauth-openshift configmap still
+// reflects an older OAuth generation than the current spec, and escalates
+// once the configmap catches up, rather than staying excused indefinitely.
+func TestOAuthConfigPendingGatesOnConfigMapGeneration(t *testing.T) {
+	oauthConfig := &configv1.OAuth{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Generation: 2},
+	}
+	oauthIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := oauthIndexer.Add(oauthConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "openshift-config-managed",
+			Name:        "oauth-openshift",
+			Annotations: map[string]string{oauthMetadataGenerationAnnotation: "1"},
+		},
+	}
+	cmIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := cmIndexer.Add(cm); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &wellKnownReadyController{
+		oauthLister:     configv1lister.NewOAuthLister(oauthIndexer),
+		configMapLister: corev1lister.NewConfigMapLister(cmIndexer),
+		results: map[string]probeResult{
+			"10.0.0.1:6443": {err: &metadataMismatchError{wellKnown: "https://10.0.0.1:6443/.well-known/oauth-authorization-server"}},
+		},
+	}
+
+	if !c.oauthConfigPending() {
+		t.Fatal("expected the mismatch to be pending while the configmap still reflects an older OAuth generation")
+	}
+
+	cm.Annotations[oauthMetadataGenerationAnnotation] = "2"
+	if err := cmIndexer.Update(cm); err != nil {
+		t.Fatal(err)
+	}
+
+	if c.oauthConfigPending() {
+		t.Fatal("expected the mismatch to escalate once the configmap reflects the current OAuth generation")
+	}
+}
+
+// TestOAuthConfigPendingWithoutConfigMapAnnotation ensures that, absent the
+// generation annotation (or its schema being unknown), oauthConfigPending
+// still falls back to the generation-keyed grace window rather than
+// treating the mismatch as permanently pending or immediately failing.
+func TestOAuthConfigPendingWithoutConfigMapAnnotation(t *testing.T) {
+	oauthConfig := &configv1.OAuth{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster", Generation: 1},
+	}
+	oauthIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{})
+	if err := oauthIndexer.Add(oauthConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	cm := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "openshift-config-managed", Name: "oauth-openshift"},
+	}
+	cmIndexer := cache.NewIndexer(cache.MetaNamespaceKeyFunc, cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc})
+	if err := cmIndexer.Add(cm); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &wellKnownReadyController{
+		oauthLister:     configv1lister.NewOAuthLister(oauthIndexer),
+		configMapLister: corev1lister.NewConfigMapLister(cmIndexer),
+		results: map[string]probeResult{
+			"10.0.0.1:6443": {err: &metadataMismatchError{wellKnown: "https://10.0.0.1:6443/.well-known/oauth-authorization-server"}},
+		},
+	}
+
+	if !c.oauthConfigPending() {
+		t.Fatal("expected the mismatch to be excused under the grace window while the reflected generation is unknown")
+	}
+
+	c.oauthGrace.since = time.Now().Add(-(oauthConfigGraceWindow + time.Second))
+	if c.oauthConfigPending() {
+		t.Fatal("expected the mismatch to escalate once the grace window elapses, even without the annotation")
+	}
+}
@@ -2,21 +2,28 @@ package readiness
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"os"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/informers"
 	corev1lister "k8s.io/client-go/listers/core/v1"
+	discoveryv1lister "k8s.io/client-go/listers/discovery/v1"
 	"k8s.io/klog"
 
 	configv1 "github.com/openshift/api/config/v1"
@@ -27,13 +34,46 @@ import (
 	routeinformer "github.com/openshift/client-go/route/informers/externalversions/route/v1"
 	routev1lister "github.com/openshift/client-go/route/listers/route/v1"
 	"github.com/openshift/library-go/pkg/controller/factory"
+	"github.com/openshift/library-go/pkg/crypto"
 	"github.com/openshift/library-go/pkg/operator/events"
 	"github.com/openshift/library-go/pkg/operator/v1helpers"
 
 	"github.com/openshift/cluster-authentication-operator/pkg/controllers/common"
 	"github.com/openshift/cluster-authentication-operator/pkg/transport"
+
+	"k8s.io/component-base/metrics"
+	"k8s.io/component-base/metrics/legacyregistry"
+)
+
+var (
+	wellKnownProbeDuration = metrics.NewHistogramVec(
+		&metrics.HistogramOpts{
+			Name:           "authentication_operator_wellknown_probe_duration_seconds",
+			Help:           "Duration in seconds of well-known endpoint probes against each kube-apiserver, labeled by the probed IP and result.",
+			Buckets:        metrics.DefBuckets,
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"ip", "result"},
+	)
+	wellKnownProbeFailuresTotal = metrics.NewCounterVec(
+		&metrics.CounterOpts{
+			Name:           "authentication_operator_wellknown_probe_failures_total",
+			Help:           "Total number of failed well-known endpoint probes against each kube-apiserver.",
+			StabilityLevel: metrics.ALPHA,
+		},
+		[]string{"ip"},
+	)
 )
 
+func init() {
+	legacyregistry.MustRegister(wellKnownProbeDuration, wellKnownProbeFailuresTotal)
+}
+
+// apiServerResourceName is the singleton config.openshift.io/v1 APIServer
+// resource whose spec.tlsSecurityProfile governs what we expect the
+// kube-apiserver to accept.
+const apiServerResourceName = "cluster"
+
 var kasServicePort int
 
 func init() {
@@ -47,13 +87,140 @@ func init() {
 	})
 }
 
+const (
+	// probeInterval is how often a healthy prober re-checks its apiserver.
+	probeInterval = 2 * time.Second
+	// probeMaxBackoff caps the interval a prober backs off to after repeated failures.
+	probeMaxBackoff = 30 * time.Second
+	// proberStaleAfter bounds how long a prober may go without reporting a result
+	// before the watchdog considers it wedged and escalates to degraded.
+	proberStaleAfter = 20 * time.Second
+	// oauthConfigGraceWindow bounds how long a well-known metadata mismatch is
+	// held at Progressing=OAuthConfigPending, rather than escalated to
+	// WellKnownEndpointDegraded, after the OAuth/cluster resource changes.
+	oauthConfigGraceWindow = 2 * time.Minute
+	// defaultProbeTimeout bounds how long a single well-known probe may run
+	// before it's treated as a failure, so one hung apiserver can't stall its
+	// prober (and, transitively, starve the staleness watchdog).
+	defaultProbeTimeout = 5 * time.Second
+	// oauthMetadataGenerationAnnotation is expected to be set, by the oauth
+	// metadata render path, on the openshift-config-managed/oauth-openshift
+	// configmap to record which OAuth/cluster spec.Generation its contents
+	// reflect. That render path lives outside this repo/checkout, so until
+	// it's wired up to stamp this annotation, reflectedOAuthGeneration will
+	// always report ok=false here and oauthConfigPending falls back to
+	// withinOAuthConfigGrace's plain timer — see the doc comments on
+	// oauthConfigPending and reflectedOAuthGeneration for that fallback.
+	oauthMetadataGenerationAnnotation = "authentication.operator.openshift.io/oauth-generation"
+	// maxConcurrentProbes bounds how many probes may be in flight across all
+	// background probers at once, so a large EndpointSlice can't open an
+	// unbounded number of simultaneous connections to the kube-apiserver.
+	maxConcurrentProbes = 8
+)
+
+// metadataMismatchError is returned by checkWellknownEndpointReady when the
+// live well-known response doesn't match the openshift-config-managed
+// configmap, as opposed to a reachability or transport failure. It's used to
+// tell the two apart when deciding whether an OAuth config rollout is still
+// in its grace period.
+type metadataMismatchError struct {
+	wellKnown string
+}
+
+func (e *metadataMismatchError) Error() string {
+	return fmt.Sprintf("the value returned by the well-known %s endpoint does not match expectations", e.wellKnown)
+}
+
+// oauthConfigGrace tracks the bounded window during which a well-known
+// metadata mismatch is attributed to a still-propagating OAuth/cluster edit
+// rather than a real degradation.
+type oauthConfigGrace struct {
+	generation int64
+	since      time.Time
+}
+
+// probeTarget is a single (address, IP family) pair to probe, derived from one
+// EndpointSlice endpoint. identity distinguishes the underlying apiserver
+// (by node or target ref) so dual-stack addresses for the same apiserver
+// don't get counted as two distinct masters.
+type probeTarget struct {
+	address  string
+	family   corev1.IPFamily
+	identity string
+}
+
+// probeResult is the latest outcome observed by a single target's background prober.
+type probeResult struct {
+	target   probeTarget
+	healthy  bool
+	err      error
+	observed time.Time
+}
+
+// ipProber continuously probes one kube-apiserver address's well-known
+// endpoint on a short interval until its context is cancelled.
+type ipProber struct {
+	target probeTarget
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
 type wellKnownReadyController struct {
-	serviceLister   corev1lister.ServiceLister
-	endpointLister  corev1lister.EndpointsLister
-	operatorClient  v1helpers.OperatorClient
-	authLister      configv1lister.AuthenticationLister
-	configMapLister corev1lister.ConfigMapLister
-	routeLister     routev1lister.RouteLister
+	serviceLister       corev1lister.ServiceLister
+	operatorClient      v1helpers.OperatorClient
+	authLister          configv1lister.AuthenticationLister
+	configMapLister     corev1lister.ConfigMapLister
+	routeLister         routev1lister.RouteLister
+	apiServerLister     configv1lister.APIServerLister
+	endpointSliceLister discoveryv1lister.EndpointSliceLister
+	oauthLister         configv1lister.OAuthLister
+
+	// proberMu guards probers, results and proberTransportFingerprint, which
+	// are written by background prober goroutines and read/reconciled from
+	// sync().
+	proberMu                   sync.Mutex
+	probers                    map[string]*ipProber
+	results                    map[string]probeResult
+	proberTransportFingerprint string
+
+	// probeSemaphore bounds the number of probes that may be in flight across
+	// all background probers at once to maxConcurrentProbes. Each target still
+	// gets its own persistent goroutine with an independent backoff schedule;
+	// only the concurrent HTTP activity is capped.
+	probeSemaphore chan struct{}
+
+	// transportMu guards the cached transport, which is rebuilt only when the
+	// resolved TLS config (spec.tlsSecurityProfile translated to
+	// MinVersion/CipherSuites) it was derived from changes.
+	transportMu             sync.Mutex
+	cachedTransport         http.RoundTripper
+	cachedForTLSFingerprint string
+
+	// watchdogOnce ensures runStalenessWatchdog is only started once, the
+	// first time sync() runs with a live controller context (the factory
+	// controller's Run context isn't available at construction time).
+	watchdogOnce sync.Once
+
+	// oauthGraceMu guards oauthGrace, which tracks the bounded window after an
+	// OAuth/cluster edit during which a metadata mismatch is treated as
+	// pending propagation rather than a real degradation.
+	oauthGraceMu sync.Mutex
+	oauthGrace   *oauthConfigGrace
+
+	// probeTimeout bounds how long a single probe's HTTP round trip may take.
+	probeTimeout time.Duration
+}
+
+// WellKnownReadyControllerOption customizes a wellKnownReadyController at
+// construction time.
+type WellKnownReadyControllerOption func(*wellKnownReadyController)
+
+// WithProbeTimeout overrides the per-probe HTTP deadline. Defaults to
+// defaultProbeTimeout.
+func WithProbeTimeout(d time.Duration) WellKnownReadyControllerOption {
+	return func(c *wellKnownReadyController) {
+		c.probeTimeout = d
+	}
 }
 
 // knownConditionNames lists all condition types used by this controller.
@@ -64,28 +231,50 @@ var knownConditionNames = sets.NewString(
 	"WellKnownAuthConfigDegraded",
 	"WellKnownProgressing",
 	"WellKnownAvailable",
+	"WellKnownEndpointDegraded",
+	"WellKnownTLSProfileDegraded",
 )
 
 func NewWellKnownReadyController(kubeInformersNamespaced informers.SharedInformerFactory, configInformers configinformer.SharedInformerFactory, routeInformer routeinformer.RouteInformer,
-	operatorClient v1helpers.OperatorClient, recorder events.Recorder) factory.Controller {
+	operatorClient v1helpers.OperatorClient, recorder events.Recorder, opts ...WellKnownReadyControllerOption) factory.Controller {
 	c := &wellKnownReadyController{
-		serviceLister:   kubeInformersNamespaced.Core().V1().Services().Lister(),
-		endpointLister:  kubeInformersNamespaced.Core().V1().Endpoints().Lister(),
-		authLister:      configInformers.Config().V1().Authentications().Lister(),
-		configMapLister: kubeInformersNamespaced.Core().V1().ConfigMaps().Lister(),
-		routeLister:     routeInformer.Lister(),
-		operatorClient:  operatorClient,
+		serviceLister:       kubeInformersNamespaced.Core().V1().Services().Lister(),
+		authLister:          configInformers.Config().V1().Authentications().Lister(),
+		configMapLister:     kubeInformersNamespaced.Core().V1().ConfigMaps().Lister(),
+		routeLister:         routeInformer.Lister(),
+		apiServerLister:     configInformers.Config().V1().APIServers().Lister(),
+		endpointSliceLister: kubeInformersNamespaced.Discovery().V1().EndpointSlices().Lister(),
+		oauthLister:         configInformers.Config().V1().OAuths().Lister(),
+		operatorClient:      operatorClient,
+		probers:             map[string]*ipProber{},
+		results:             map[string]probeResult{},
+		probeTimeout:        defaultProbeTimeout,
+		probeSemaphore:      make(chan struct{}, maxConcurrentProbes),
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
 
 	return factory.New().ResyncEvery(30*time.Second).WithInformers(
 		kubeInformersNamespaced.Core().V1().Services().Informer(),
-		kubeInformersNamespaced.Core().V1().Endpoints().Informer(),
 		configInformers.Config().V1().Authentications().Informer(),
+		configInformers.Config().V1().APIServers().Informer(),
+		configInformers.Config().V1().OAuths().Informer(),
+		kubeInformersNamespaced.Discovery().V1().EndpointSlices().Informer(),
 		routeInformer.Informer(),
 	).WithSync(c.sync).ToController("WellKnownReadyController", recorder.WithComponentSuffix("wellknown-ready-controller"))
 }
 
 func (c *wellKnownReadyController) sync(ctx context.Context, controllerContext factory.SyncContext) error {
+	// sync's ctx lives for as long as the controller runs (it's derived from
+	// the factory controller's Run context), so it's the right context to
+	// anchor the staleness watchdog and background probers to. It isn't
+	// available at construction time, so start the watchdog here instead.
+	c.watchdogOnce.Do(func() {
+		go c.runStalenessWatchdog(ctx)
+	})
+
 	foundConditions := []operatorv1.OperatorCondition{}
 
 	authConfig, configConditions := common.GetAuthConfig(c.authLister, "WellKnownAuthConfig")
@@ -100,19 +289,49 @@ func (c *wellKnownReadyController) sync(ctx context.Context, controllerContext f
 		if err != nil {
 			return err
 		}
-		if err := c.isWellknownEndpointsReady(spec, authConfig, route); err != nil {
-			foundConditions = append(foundConditions, operatorv1.OperatorCondition{
-				Type:    "WellKnownProgressing",
-				Status:  operatorv1.ConditionTrue,
-				Reason:  "NotReady",
-				Message: fmt.Sprintf("The well-known endpoint is not yet avaiable: %s", err.Error()),
-			})
-			foundConditions = append(foundConditions, operatorv1.OperatorCondition{
-				Type:    "WellKnownAvailable",
-				Status:  operatorv1.ConditionFalse,
-				Reason:  "NotReady",
-				Message: fmt.Sprintf("The well-known endpoint is not yet available: %s", err.Error()),
-			})
+		tlsProfileCondition, tlsErr := c.tlsProfileCondition()
+		foundConditions = append(foundConditions, tlsProfileCondition)
+
+		if tlsErr == nil {
+			err := c.isWellknownEndpointsReady(ctx, spec, authConfig, route)
+
+			pending := false
+			if err != nil {
+				pending = c.oauthConfigPending()
+			} else {
+				c.clearOAuthConfigGrace()
+			}
+
+			if err != nil {
+				progressingReason := "NotReady"
+				if pending {
+					progressingReason = "OAuthConfigPending"
+				}
+				foundConditions = append(foundConditions, operatorv1.OperatorCondition{
+					Type:    "WellKnownProgressing",
+					Status:  operatorv1.ConditionTrue,
+					Reason:  progressingReason,
+					Message: fmt.Sprintf("The well-known endpoint is not yet avaiable: %s", err.Error()),
+				})
+				foundConditions = append(foundConditions, operatorv1.OperatorCondition{
+					Type:    "WellKnownAvailable",
+					Status:  operatorv1.ConditionFalse,
+					Reason:  "NotReady",
+					Message: fmt.Sprintf("The well-known endpoint is not yet available: %s", err.Error()),
+				})
+			}
+
+			if pending {
+				foundConditions = append(foundConditions, operatorv1.OperatorCondition{
+					Type:   "WellKnownEndpointDegraded",
+					Status: operatorv1.ConditionFalse,
+					Reason: "OAuthConfigPending",
+				})
+			} else {
+				foundConditions = append(foundConditions, c.degradedConditionFromProbers()...)
+			}
+		} else {
+			c.stopAllProbers()
 		}
 	} else {
 		// if the prereqs aren't present we don't have well-known correct
@@ -122,41 +341,45 @@ func (c *wellKnownReadyController) sync(ctx context.Context, controllerContext f
 			Reason:  "PrereqsNotReady",
 			Message: "THe well-known endpoint prereqs are not yet available",
 		})
+		c.stopAllProbers()
 	}
 
 	return common.UpdateControllerConditions(c.operatorClient, knownConditionNames, foundConditions)
 }
 
-func (c *wellKnownReadyController) isWellknownEndpointsReady(spec *operatorv1.OperatorSpec, authConfig *configv1.Authentication, route *routev1.Route) error {
+func (c *wellKnownReadyController) isWellknownEndpointsReady(ctx context.Context, spec *operatorv1.OperatorSpec, authConfig *configv1.Authentication, route *routev1.Route) error {
 	// don't perform this check when OAuthMetadata reference is set up
 	// leave those cases to KAS-o which handles these cases
 	// the operator manages the metadata if specifically requested and by default
 	isOperatorManagedMetadata := authConfig.Spec.Type == configv1.AuthenticationTypeIntegratedOAuth || len(authConfig.Spec.Type) == 0
 	if userMetadataConfig := authConfig.Spec.OAuthMetadata.Name; !isOperatorManagedMetadata || len(userMetadataConfig) != 0 {
+		c.stopAllProbers()
 		return nil
 	}
 
-	caData, err := ioutil.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/ca.crt")
+	rt, transportFingerprint, err := c.transportForProbing()
 	if err != nil {
-		return fmt.Errorf("failed to read SA ca.crt: %v", err)
+		return fmt.Errorf("failed to build transport for probing: %v", err)
 	}
 
-	// pass the KAS service name for SNI
-	rt, err := transport.TransportFor("kubernetes.default.svc", caData, nil, nil)
+	targets, err := c.getAPIServerIPs()
 	if err != nil {
-		return fmt.Errorf("failed to build transport for SA ca.crt: %v", err)
+		return fmt.Errorf("failed to get API server IPs: %v", err)
 	}
 
-	ips, err := c.getAPIServerIPs()
-	if err != nil {
-		return fmt.Errorf("failed to get API server IPs: %v", err)
+	c.reconcileProbers(ctx, targets, rt, transportFingerprint, route)
+
+	failing := c.failingProbeIPs()
+	if len(failing) != 0 {
+		return fmt.Errorf("well-known endpoint unreachable on: %v", failing)
 	}
 
-	for _, ip := range ips {
-		err := c.checkWellknownEndpointReady(ip, rt, route)
-		if err != nil {
-			return err
-		}
+	// count distinct apiservers, not raw (address, family) probe targets, so a
+	// dual-stack apiserver publishing both an IPv4 and an IPv6 address isn't
+	// counted twice.
+	distinctMasters := sets.NewString()
+	for _, target := range targets {
+		distinctMasters.Insert(target.identity)
 	}
 
 	// if we don't have the min number of masters, this is actually ok, however Clayton has draw a hardline on starting tests as soon as all operators are Available=true
@@ -165,17 +388,494 @@ func (c *wellKnownReadyController) isWellknownEndpointsReady(spec *operatorv1.Op
 	// acceptable for the kube-apiserver to do during a rollout.  However, because we are not allowed to merge code that ensures
 	// a stable kube-apiserver and because rewriting client tests like e2e-cmd is impractical, we are left trying to enforce
 	// this by delaying our availability because it's a backdoor into slowing down the test suite start time to gain stability.
-	if expectedMinNumber := getExpectedMinimumNumberOfMasters(spec); len(ips) < expectedMinNumber {
-		return fmt.Errorf("need at least %d kube-apiservers, got %d", expectedMinNumber, len(ips))
+	if expectedMinNumber := getExpectedMinimumNumberOfMasters(spec); distinctMasters.Len() < expectedMinNumber {
+		return fmt.Errorf("need at least %d kube-apiservers, got %d", expectedMinNumber, distinctMasters.Len())
 	}
 
 	return nil
 }
 
-func (c *wellKnownReadyController) checkWellknownEndpointReady(apiIP string, rt http.RoundTripper, route *routev1.Route) error {
+// tlsProfileCondition validates the cluster's configured tlsSecurityProfile and
+// reports any problem building a tls.Config from it as WellKnownTLSProfileDegraded.
+// It returns a non-nil error when probing should be skipped for this sync because
+// the profile can't currently be translated.
+func (c *wellKnownReadyController) tlsProfileCondition() (operatorv1.OperatorCondition, error) {
+	_, err := c.currentTLSConfig()
+	if err != nil {
+		return operatorv1.OperatorCondition{
+			Type:    "WellKnownTLSProfileDegraded",
+			Status:  operatorv1.ConditionTrue,
+			Reason:  "InvalidTLSProfile",
+			Message: err.Error(),
+		}, err
+	}
+
+	return operatorv1.OperatorCondition{
+		Type:   "WellKnownTLSProfileDegraded",
+		Status: operatorv1.ConditionFalse,
+		Reason: "AsExpected",
+	}, nil
+}
+
+// currentTLSConfig reads the cluster's APIServer resource and translates its
+// spec.tlsSecurityProfile into a tls.Config, defaulting to the Intermediate
+// profile when none is set (mirroring the kube-apiserver's own default).
+func (c *wellKnownReadyController) currentTLSConfig() (*tls.Config, error) {
+	apiServer, err := c.apiServerLister.Get(apiServerResourceName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get APIServer/%s: %v", apiServerResourceName, err)
+	}
+
+	profile := apiServer.Spec.TLSSecurityProfile
+	if profile == nil {
+		profile = &configv1.TLSSecurityProfile{Type: configv1.TLSProfileIntermediateType}
+	}
+
+	return tlsConfigForProfile(profile)
+}
+
+// tlsConfigForProfile mirrors library-go's crypto.SecureTLSConfigFromProfile,
+// translating an APIServer tlsSecurityProfile into the MinVersion and
+// CipherSuites we expect the kube-apiserver to accept connections with.
+func tlsConfigForProfile(profile *configv1.TLSSecurityProfile) (*tls.Config, error) {
+	var spec *configv1.TLSProfileSpec
+	if profile.Type == configv1.TLSProfileCustomType {
+		if profile.Custom == nil {
+			return nil, fmt.Errorf("invalid tlsSecurityProfile: type is Custom but custom profile is unset")
+		}
+		spec = &profile.Custom.TLSProfileSpec
+	} else {
+		var ok bool
+		spec, ok = configv1.TLSProfiles[profile.Type]
+		if !ok {
+			return nil, fmt.Errorf("unsupported tlsSecurityProfile type: %q", profile.Type)
+		}
+	}
+
+	minVersion, err := crypto.TLSVersion(string(spec.MinTLSVersion))
+	if err != nil {
+		return nil, fmt.Errorf("invalid tlsSecurityProfile minTLSVersion %q: %v", spec.MinTLSVersion, err)
+	}
+
+	cipherSuites, err := crypto.CipherSuitesForNames(spec.Ciphers)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tlsSecurityProfile ciphers %v: %v", spec.Ciphers, err)
+	}
+
+	return &tls.Config{
+		MinVersion:   minVersion,
+		CipherSuites: cipherSuites,
+	}, nil
+}
+
+// transportForProbing returns the cached transport used to probe the
+// well-known endpoint, rebuilding it only when the resolved TLS config it was
+// derived from (the MinVersion/CipherSuites translated from
+// spec.tlsSecurityProfile) has changed since the last sync, along with a
+// fingerprint of that config so callers can tell probers using a stale
+// transport to restart. Keying on the resolved TLS config, rather than the
+// APIServer's ResourceVersion, avoids rebuilding (and restarting every
+// prober) on unrelated status/metadata writes to the APIServer resource.
+func (c *wellKnownReadyController) transportForProbing() (http.RoundTripper, string, error) {
+	tlsConfig, err := c.currentTLSConfig()
+	if err != nil {
+		return nil, "", err
+	}
+	fingerprint := tlsConfigFingerprint(tlsConfig)
+
+	c.transportMu.Lock()
+	defer c.transportMu.Unlock()
+
+	if c.cachedTransport != nil && c.cachedForTLSFingerprint == fingerprint {
+		return c.cachedTransport, fingerprint, nil
+	}
+
+	caData, err := ioutil.ReadFile("/var/run/secrets/kubernetes.io/serviceaccount/ca.crt")
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read SA ca.crt: %v", err)
+	}
+
+	// pass the KAS service name for SNI
+	rt, err := transport.TransportFor("kubernetes.default.svc", caData, nil, nil, transport.WithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to build transport for SA ca.crt: %v", err)
+	}
+
+	c.cachedTransport = rt
+	c.cachedForTLSFingerprint = fingerprint
+	return rt, fingerprint, nil
+}
+
+// tlsConfigFingerprint returns a string that uniquely identifies a tls.Config
+// built by tlsConfigForProfile, so callers can detect when the resolved
+// MinVersion/CipherSuites have actually changed.
+func tlsConfigFingerprint(cfg *tls.Config) string {
+	ciphers := append([]uint16{}, cfg.CipherSuites...)
+	sort.Slice(ciphers, func(i, j int) bool { return ciphers[i] < ciphers[j] })
+
+	parts := make([]string, 0, len(ciphers)+1)
+	parts = append(parts, strconv.Itoa(int(cfg.MinVersion)))
+	for _, cipher := range ciphers {
+		parts = append(parts, strconv.Itoa(int(cipher)))
+	}
+	return strings.Join(parts, ",")
+}
+
+// reconcileProbers starts a background prober for every target that doesn't
+// already have one running, and stops/removes probers for targets that are no
+// longer present (e.g. the EndpointSlices changed) or whose transport is now
+// stale (transportFingerprint no longer matches the resolved TLS config the
+// running probers were started with, e.g. after a tlsSecurityProfile edit), so
+// every prober ends up using rt. It must be called from a single goroutine at
+// a time (true today since sync() is invoked serially by the factory
+// controller).
+func (c *wellKnownReadyController) reconcileProbers(ctx context.Context, targets []probeTarget, rt http.RoundTripper, transportFingerprint string, route *routev1.Route) {
+	wanted := sets.NewString()
+	for _, target := range targets {
+		wanted.Insert(target.address)
+	}
+
+	c.proberMu.Lock()
+	transportChanged := c.proberTransportFingerprint != transportFingerprint
+	c.proberTransportFingerprint = transportFingerprint
+	stale := make([]*ipProber, 0)
+	for address, p := range c.probers {
+		if !wanted.Has(address) || transportChanged {
+			stale = append(stale, p)
+			delete(c.probers, address)
+		}
+	}
+	c.proberMu.Unlock()
+
+	// Cancel and wait for stale probers without holding proberMu: a prober
+	// blocked in an in-flight RoundTrip needs to acquire proberMu itself (via
+	// recordResult) to observe the cancellation and finish, so holding the
+	// lock here would deadlock against it.
+	stopProbers(stale)
+
+	c.proberMu.Lock()
+	for _, p := range stale {
+		delete(c.results, p.target.address)
+	}
+	for _, target := range targets {
+		if _, exists := c.probers[target.address]; exists {
+			continue
+		}
+		c.startProberLocked(ctx, target, rt, route)
+	}
+	c.proberMu.Unlock()
+}
+
+// stopProbers cancels every prober and waits for its goroutine to exit.
+// Callers must not hold proberMu, since a prober's goroutine needs it to
+// record its final result before it can observe the cancellation.
+func stopProbers(probers []*ipProber) {
+	for _, p := range probers {
+		p.cancel()
+		<-p.done
+	}
+}
+
+// startProberLocked launches the background goroutine for target. c.proberMu
+// must be held by the caller.
+func (c *wellKnownReadyController) startProberLocked(ctx context.Context, target probeTarget, rt http.RoundTripper, route *routev1.Route) {
+	proberCtx, cancel := context.WithCancel(ctx)
+	p := &ipProber{
+		target: target,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	c.probers[target.address] = p
+
+	// Seed an unhealthy result so this target counts as failing until its
+	// first real probe completes, rather than being silently absent from
+	// results and letting failingProbeIPs/isWellknownEndpointsReady report
+	// ready before a single probe has actually contacted the apiserver.
+	c.results[target.address] = probeResult{
+		target:   target,
+		healthy:  false,
+		err:      fmt.Errorf("no probe has completed yet for %s", target.address),
+		observed: time.Now(),
+	}
+
+	go func() {
+		defer close(p.done)
+		c.runProber(proberCtx, target, rt, route)
+	}()
+}
+
+// runProber is the body of a background prober goroutine: it probes target on
+// probeInterval while healthy, backing off exponentially (capped at
+// probeMaxBackoff) on consecutive failures, until its context is cancelled.
+func (c *wellKnownReadyController) runProber(ctx context.Context, target probeTarget, rt http.RoundTripper, route *routev1.Route) {
+	interval := probeInterval
+	for {
+		select {
+		case c.probeSemaphore <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+
+		probeCtx, cancel := context.WithTimeout(ctx, c.probeTimeout)
+		start := time.Now()
+		err := c.checkWellknownEndpointReady(probeCtx, target.address, rt, route)
+		duration := time.Since(start)
+		cancel()
+		<-c.probeSemaphore
+
+		result := "success"
+		if err != nil {
+			result = "failure"
+			wellKnownProbeFailuresTotal.WithLabelValues(target.address).Inc()
+		}
+		wellKnownProbeDuration.WithLabelValues(target.address, result).Observe(duration.Seconds())
+
+		c.recordResult(probeResult{
+			target:   target,
+			healthy:  err == nil,
+			err:      err,
+			observed: time.Now(),
+		})
+
+		if err == nil {
+			interval = probeInterval
+		} else {
+			interval *= 2
+			if interval > probeMaxBackoff {
+				interval = probeMaxBackoff
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+func (c *wellKnownReadyController) recordResult(r probeResult) {
+	c.proberMu.Lock()
+	defer c.proberMu.Unlock()
+	c.results[r.target.address] = r
+}
+
+// runStalenessWatchdog periodically checks that every running prober has
+// reported a result recently. A prober that has gone quiet (e.g. wedged in a
+// RoundTrip call) is treated as failing rather than silently masking the
+// last-known-good result forever.
+func (c *wellKnownReadyController) runStalenessWatchdog(ctx context.Context) {
+	ticker := time.NewTicker(proberStaleAfter / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.markStaleProbersUnhealthy()
+		}
+	}
+}
+
+func (c *wellKnownReadyController) markStaleProbersUnhealthy() {
+	c.proberMu.Lock()
+	defer c.proberMu.Unlock()
+	now := time.Now()
+	for address, r := range c.results {
+		// A failing prober may legitimately wait up to probeMaxBackoff between
+		// probes; keep its last real error rather than overwriting it.
+		if !r.healthy {
+			continue
+		}
+		if now.Sub(r.observed) > proberStaleAfter {
+			c.results[address] = probeResult{
+				target:   r.target,
+				healthy:  false,
+				err:      fmt.Errorf("prober has not reported a result in over %s, assuming wedged", proberStaleAfter),
+				observed: r.observed,
+			}
+		}
+	}
+}
+
+// failingProbeIPs returns the sorted list of IP:port targets whose latest
+// result is unhealthy.
+func (c *wellKnownReadyController) failingProbeIPs() []string {
+	c.proberMu.Lock()
+	defer c.proberMu.Unlock()
+
+	failing := []string{}
+	for address, r := range c.results {
+		if !r.healthy {
+			failing = append(failing, address)
+		}
+	}
+	sort.Strings(failing)
+	return failing
+}
+
+// oauthConfigPending reports whether the current probe failures are entirely
+// due to well-known metadata mismatches (as opposed to reachability errors)
+// and, if so, whether we're still within the grace window since the OAuth/
+// cluster resource was last observed to change. The grace clock is keyed by
+// the OAuth resource's spec.Generation, which (unlike resourceVersion) only
+// advances on spec edits, not on unrelated status/metadata churn from other
+// controllers. It's further gated on the oauth-openshift configmap actually
+// still reflecting an older generation: once the configmap catches up, a
+// persisting mismatch is a real problem, not a pending rollout.
+//
+// That deterministic gate only fires once reflectedOAuthGeneration can read
+// oauthMetadataGenerationAnnotation off the configmap; the render path that
+// stamps it lives in a different repo and isn't wired up here, so today this
+// always falls through to the plain withinOAuthConfigGrace timer. That
+// fallback is deliberate, not a dead path: TestOAuthConfigPendingWithoutConfigMapAnnotation
+// covers it directly, and it's the same behavior oauthConfigGraceWindow was
+// introduced to provide before this annotation existed.
+func (c *wellKnownReadyController) oauthConfigPending() bool {
+	if !c.allFailingResultsAreMismatch() {
+		return false
+	}
+
+	oauthConfig, err := c.oauthLister.Get("cluster")
+	if err != nil {
+		return false
+	}
+
+	cm, err := c.configMapLister.ConfigMaps("openshift-config-managed").Get("oauth-openshift")
+	if err != nil {
+		return false
+	}
+
+	if reflectedGeneration, ok := reflectedOAuthGeneration(cm); ok && reflectedGeneration >= oauthConfig.Generation {
+		c.clearOAuthConfigGrace()
+		return false
+	}
+
+	return c.withinOAuthConfigGrace(oauthConfig.Generation)
+}
+
+// reflectedOAuthGeneration returns the OAuth/cluster spec.Generation the
+// oauth-openshift configmap's contents were rendered from, if known. ok is
+// false whenever the annotation is absent or unparsable, which today is
+// always, since nothing in this checkout stamps
+// oauthMetadataGenerationAnnotation yet (see its doc comment) — callers must
+// treat ok=false as "unknown", not "mismatch", and fall back accordingly.
+func reflectedOAuthGeneration(cm *corev1.ConfigMap) (int64, bool) {
+	raw, ok := cm.Annotations[oauthMetadataGenerationAnnotation]
+	if !ok {
+		return 0, false
+	}
+	generation, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return generation, true
+}
+
+func (c *wellKnownReadyController) allFailingResultsAreMismatch() bool {
+	c.proberMu.Lock()
+	defer c.proberMu.Unlock()
+
+	sawFailure := false
+	for _, r := range c.results {
+		if r.healthy {
+			continue
+		}
+		sawFailure = true
+		var mismatchErr *metadataMismatchError
+		if !errors.As(r.err, &mismatchErr) {
+			return false
+		}
+	}
+	return sawFailure
+}
+
+func (c *wellKnownReadyController) withinOAuthConfigGrace(oauthGeneration int64) bool {
+	c.oauthGraceMu.Lock()
+	defer c.oauthGraceMu.Unlock()
+
+	if c.oauthGrace == nil || c.oauthGrace.generation != oauthGeneration {
+		c.oauthGrace = &oauthConfigGrace{generation: oauthGeneration, since: time.Now()}
+		return true
+	}
+
+	return time.Since(c.oauthGrace.since) < oauthConfigGraceWindow
+}
+
+func (c *wellKnownReadyController) clearOAuthConfigGrace() {
+	c.oauthGraceMu.Lock()
+	defer c.oauthGraceMu.Unlock()
+	c.oauthGrace = nil
+}
+
+// degradedConditionFromProbers surfaces per-instance probe failures as a
+// single WellKnownEndpointDegraded condition listing every failing endpoint,
+// so a single flaky apiserver doesn't hide behind an aggregate message.
+func (c *wellKnownReadyController) degradedConditionFromProbers() []operatorv1.OperatorCondition {
+	c.proberMu.Lock()
+	defer c.proberMu.Unlock()
+
+	failures := []probeResult{}
+	for _, r := range c.results {
+		if !r.healthy {
+			failures = append(failures, r)
+		}
+	}
+	if len(failures) == 0 {
+		return []operatorv1.OperatorCondition{
+			{
+				Type:   "WellKnownEndpointDegraded",
+				Status: operatorv1.ConditionFalse,
+				Reason: "AsExpected",
+			},
+		}
+	}
+
+	sort.Slice(failures, func(i, j int) bool { return failures[i].target.address < failures[j].target.address })
+
+	msg := ""
+	for _, f := range failures {
+		if f.target.family == corev1.IPv6Protocol {
+			msg += fmt.Sprintf("IPv6 well-known unreachable on %s: %v\n", f.target.address, f.err)
+		} else {
+			msg += fmt.Sprintf("IPv4 well-known unreachable on %s: %v\n", f.target.address, f.err)
+		}
+	}
+
+	return []operatorv1.OperatorCondition{
+		{
+			Type:    "WellKnownEndpointDegraded",
+			Status:  operatorv1.ConditionTrue,
+			Reason:  "WellKnownEndpointUnreachable",
+			Message: msg,
+		},
+	}
+}
+
+// stopAllProbers cancels and removes every running prober, e.g. when the
+// controller's prereqs stop being met.
+func (c *wellKnownReadyController) stopAllProbers() {
+	c.proberMu.Lock()
+	stale := make([]*ipProber, 0, len(c.probers))
+	for _, p := range c.probers {
+		stale = append(stale, p)
+	}
+	c.probers = map[string]*ipProber{}
+	c.proberMu.Unlock()
+
+	stopProbers(stale)
+
+	c.proberMu.Lock()
+	for _, p := range stale {
+		delete(c.results, p.target.address)
+	}
+	c.proberMu.Unlock()
+}
+
+func (c *wellKnownReadyController) checkWellknownEndpointReady(ctx context.Context, apiIP string, rt http.RoundTripper, route *routev1.Route) error {
 	wellKnown := "https://" + apiIP + "/.well-known/oauth-authorization-server"
 
-	req, err := http.NewRequest(http.MethodGet, wellKnown, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
 	if err != nil {
 		return fmt.Errorf("failed to build request to well-known %s: %v", wellKnown, err)
 	}
@@ -205,7 +905,7 @@ func (c *wellKnownReadyController) checkWellknownEndpointReady(apiIP string, rt
 	}
 
 	if !reflect.DeepEqual(expectedMetadata, receivedValues) {
-		return fmt.Errorf("the value returned by the well-known %s endpoint does not match expectations", wellKnown)
+		return &metadataMismatchError{wellKnown: wellKnown}
 	}
 
 	return nil
@@ -239,16 +939,42 @@ func getKASTargetPortFromService(service *corev1.Service) (int, bool) {
 	return 0, false
 }
 
-func subsetHasKASTargetPort(subset corev1.EndpointSubset, targetPort int) bool {
-	for _, port := range subset.Ports {
-		if port.Protocol == corev1.ProtocolTCP && int(port.Port) == targetPort {
+func endpointSliceHasKASTargetPort(slice *discoveryv1.EndpointSlice, targetPort int) bool {
+	for _, port := range slice.Ports {
+		if port.Port == nil || port.Protocol == nil {
+			continue
+		}
+		if *port.Protocol == corev1.ProtocolTCP && int(*port.Port) == targetPort {
 			return true
 		}
 	}
 	return false
 }
 
-func (c *wellKnownReadyController) getAPIServerIPs() ([]string, error) {
+// endpointIdentity returns a key that identifies the underlying apiserver (as
+// opposed to one of its addresses), so a dual-stack apiserver's IPv4 and IPv6
+// addresses resolve to the same identity.
+func endpointIdentity(ep discoveryv1.Endpoint) string {
+	if ep.TargetRef != nil && len(ep.TargetRef.Name) != 0 {
+		return ep.TargetRef.Name
+	}
+	if ep.NodeName != nil && len(*ep.NodeName) != 0 {
+		return *ep.NodeName
+	}
+	if len(ep.Addresses) != 0 {
+		return ep.Addresses[0]
+	}
+	return ""
+}
+
+func endpointReady(ep discoveryv1.Endpoint) bool {
+	return ep.Conditions.Ready == nil || *ep.Conditions.Ready
+}
+
+// getAPIServerIPs returns one probe target per (address, IP family) pair
+// published by the "kubernetes" service's EndpointSlices, so both the IPv4 and
+// IPv6 addresses of a dual-stack apiserver get probed.
+func (c *wellKnownReadyController) getAPIServerIPs() ([]probeTarget, error) {
 	kasService, err := c.serviceLister.Services(corev1.NamespaceDefault).Get("kubernetes")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get kube api server service: %v", err)
@@ -259,26 +985,43 @@ func (c *wellKnownReadyController) getAPIServerIPs() ([]string, error) {
 		return nil, fmt.Errorf("unable to find kube api server service target port: %#v", kasService)
 	}
 
-	kasEndpoint, err := c.endpointLister.Endpoints(corev1.NamespaceDefault).Get("kubernetes")
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: "kubernetes"})
+	slices, err := c.endpointSliceLister.EndpointSlices(corev1.NamespaceDefault).List(selector)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get kube api server endpointLister: %v", err)
+		return nil, fmt.Errorf("failed to list kube api server endpointslices: %v", err)
 	}
 
-	for _, subset := range kasEndpoint.Subsets {
-		if !subsetHasKASTargetPort(subset, targetPort) {
+	targets := []probeTarget{}
+	for _, slice := range slices {
+		if !endpointSliceHasKASTargetPort(slice, targetPort) {
 			continue
 		}
 
-		if len(subset.NotReadyAddresses) != 0 || len(subset.Addresses) == 0 {
-			return nil, fmt.Errorf("kube api server endpointLister is not ready: %#v", kasEndpoint)
+		family := corev1.IPv4Protocol
+		if slice.AddressType == discoveryv1.AddressTypeIPv6 {
+			family = corev1.IPv6Protocol
+		} else if slice.AddressType != discoveryv1.AddressTypeIPv4 {
+			continue
 		}
 
-		ips := make([]string, 0, len(subset.Addresses))
-		for _, address := range subset.Addresses {
-			ips = append(ips, net.JoinHostPort(address.IP, strconv.Itoa(targetPort)))
+		for _, ep := range slice.Endpoints {
+			if !endpointReady(ep) {
+				continue
+			}
+			identity := endpointIdentity(ep)
+			for _, address := range ep.Addresses {
+				targets = append(targets, probeTarget{
+					address:  net.JoinHostPort(address, strconv.Itoa(targetPort)),
+					family:   family,
+					identity: identity,
+				})
+			}
 		}
-		return ips, nil
 	}
 
-	return nil, fmt.Errorf("unable to find kube api server endpointLister port: %#v", kasEndpoint)
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("unable to find any ready kube api server endpointslice addresses for service %#v", kasService)
+	}
+
+	return targets, nil
 }
@@ -0,0 +1,128 @@
+package readiness
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// blockingRoundTripper simulates an in-flight probe that only returns once
+// its request's context is cancelled, so tests can deterministically catch a
+// prober mid-RoundTrip before asking reconcileProbers/stopAllProbers to stop it.
+type blockingRoundTripper struct {
+	started chan struct{}
+	once    sync.Once
+}
+
+func newBlockingRoundTripper() *blockingRoundTripper {
+	return &blockingRoundTripper{started: make(chan struct{})}
+}
+
+func (b *blockingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	b.once.Do(func() { close(b.started) })
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+// TestReconcileProbersDoesNotDeadlockOnInFlightProbe is a regression test for
+// a lock-ordering deadlock: reconcileProbers used to hold proberMu across
+// cancel()+<-done, but a cancelled prober needs proberMu itself (via
+// recordResult) to finish its current iteration and close done.
+func TestReconcileProbersDoesNotDeadlockOnInFlightProbe(t *testing.T) {
+	c := &wellKnownReadyController{
+		probers:        map[string]*ipProber{},
+		results:        map[string]probeResult{},
+		probeTimeout:   time.Hour, // rely on context cancellation, not the timeout
+		probeSemaphore: make(chan struct{}, maxConcurrentProbes),
+	}
+
+	rt := newBlockingRoundTripper()
+	target := probeTarget{address: "127.0.0.1:6443", family: corev1.IPv4Protocol, identity: "master-1"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c.reconcileProbers(ctx, []probeTarget{target}, rt, "rv-1", nil)
+
+	select {
+	case <-rt.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("prober never issued its probe")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		// dropping the target stops the in-flight prober
+		c.reconcileProbers(ctx, nil, rt, "rv-1", nil)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reconcileProbers deadlocked stopping an in-flight prober")
+	}
+
+	c.proberMu.Lock()
+	defer c.proberMu.Unlock()
+	if len(c.probers) != 0 || len(c.results) != 0 {
+		t.Fatalf("expected the stopped prober to be fully removed, got probers=%v results=%v", c.probers, c.results)
+	}
+}
+
+// TestStopAllProbersDoesNotDeadlockOnInFlightProbe mirrors the above for
+// stopAllProbers, which has the same cancel()+<-done pattern.
+func TestStopAllProbersDoesNotDeadlockOnInFlightProbe(t *testing.T) {
+	c := &wellKnownReadyController{
+		probers:        map[string]*ipProber{},
+		results:        map[string]probeResult{},
+		probeTimeout:   time.Hour,
+		probeSemaphore: make(chan struct{}, maxConcurrentProbes),
+	}
+
+	rt := newBlockingRoundTripper()
+	target := probeTarget{address: "127.0.0.1:6443", family: corev1.IPv4Protocol, identity: "master-1"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c.reconcileProbers(ctx, []probeTarget{target}, rt, "rv-1", nil)
+
+	select {
+	case <-rt.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("prober never issued its probe")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		c.stopAllProbers()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stopAllProbers deadlocked stopping an in-flight prober")
+	}
+}
+
+func TestTLSConfigForProfileCustomNil(t *testing.T) {
+	_, err := tlsConfigForProfile(&configv1.TLSSecurityProfile{Type: configv1.TLSProfileCustomType})
+	if err == nil {
+		t.Fatal("expected an error for a Custom profile with no Custom spec set")
+	}
+}
+
+func TestTLSConfigForProfileUnsupportedType(t *testing.T) {
+	_, err := tlsConfigForProfile(&configv1.TLSSecurityProfile{Type: configv1.TLSProfileType("NotARealProfile")})
+	if err == nil {
+		t.Fatal("expected an error for an unsupported tlsSecurityProfile type")
+	}
+}
@@ -0,0 +1,60 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+)
+
+// Option customizes the tls.Config used by a transport built with TransportFor.
+type Option func(*tls.Config)
+
+// WithTLSConfig overlays the MinVersion and CipherSuites of cfg onto the
+// transport's tls.Config, e.g. to honor an APIServer's tlsSecurityProfile.
+// A nil cfg, or one with its fields left at their zero value, leaves the
+// corresponding tls.Config field untouched.
+func WithTLSConfig(cfg *tls.Config) Option {
+	return func(tlsConfig *tls.Config) {
+		if cfg == nil {
+			return
+		}
+		if cfg.MinVersion != 0 {
+			tlsConfig.MinVersion = cfg.MinVersion
+		}
+		if len(cfg.CipherSuites) != 0 {
+			tlsConfig.CipherSuites = cfg.CipherSuites
+		}
+	}
+}
+
+// TransportFor builds an http.RoundTripper that trusts caData, optionally
+// authenticates with the client certData/keyData pair, and verifies the peer
+// against serverName. serverName is useful when the address being dialed
+// (e.g. a Service's ClusterIP or a bare pod IP) isn't covered by the server's
+// certificate SANs.
+func TransportFor(serverName string, caData, certData, keyData []byte, opts ...Option) (http.RoundTripper, error) {
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caData) {
+		return nil, fmt.Errorf("unable to parse CA data for %s", serverName)
+	}
+
+	tlsConfig := &tls.Config{
+		RootCAs:    pool,
+		ServerName: serverName,
+	}
+
+	if len(certData) != 0 && len(keyData) != 0 {
+		cert, err := tls.X509KeyPair(certData, keyData)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load client certificate for %s: %v", serverName, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	for _, opt := range opts {
+		opt(tlsConfig)
+	}
+
+	return &http.Transport{TLSClientConfig: tlsConfig}, nil
+}